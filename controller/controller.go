@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"github.com/hexdecteam/easegateway-types/pipelines"
+	"github.com/hexdecteam/easegateway-types/plugins"
+)
+
+// TrafficControllerStatus is a point-in-time snapshot of a single pipeline's
+// traffic state as tracked by TrafficController.
+type TrafficControllerStatus struct {
+	Namespace  string
+	Running    bool
+	MuxEntries []*plugins.HTTPMuxEntry
+	LastError  error
+}
+
+// TrafficController owns a set of HTTPMux instances together with the
+// PipelineContext of every pipeline currently serving traffic through them.
+// It is the single point that mutates mux entries, guaranteeing a pipeline's
+// entries are added only once its PipelineContext.Ready reports true and
+// removed strictly before the PipelineContext is closed, so mux state and
+// pipeline lifecycle never race against each other.
+//
+// Pipelines are grouped by namespace, which allows the same HTTPMux to host
+// multiple, independently managed pipeline groups (for example one group per
+// declarative YAML apply).
+type TrafficController interface {
+	// CreatePipeline registers a new pipeline group under namespace, polling
+	// ctx.Ready and adding entriesAdding to mux only once it returns true.
+	// CreatePipeline fails if namespace already exists.
+	CreatePipeline(namespace string, ctx pipelines.PipelineContext,
+		mux plugins.HTTPMux, entriesAdding []*plugins.HTTPMuxEntry) error
+	// UpdatePipeline atomically replaces the mux entries of an existing
+	// pipeline group, never leaving the mux without a matching entry for an
+	// in-flight route.
+	UpdatePipeline(namespace string, entriesUpdating []*plugins.HTTPMuxEntry) error
+	// DeletePipeline removes namespace's mux entries and then closes its
+	// PipelineContext, in that order.
+	DeletePipeline(namespace string) error
+	// ReloadPipeline swaps namespace to a new PipelineContext and entry set,
+	// applying the same ready-before-add, close-after-delete ordering as
+	// CreatePipeline and DeletePipeline combined.
+	ReloadPipeline(namespace string, ctx pipelines.PipelineContext,
+		entriesReloading []*plugins.HTTPMuxEntry) error
+
+	// Status returns the current snapshot of namespace, or an error if the
+	// namespace is unknown.
+	Status(namespace string) (*TrafficControllerStatus, error)
+	// Statuses returns a snapshot of every namespace currently tracked.
+	Statuses() map[string]*TrafficControllerStatus
+
+	// Close tears down every tracked pipeline group.
+	Close()
+}
@@ -0,0 +1,194 @@
+package pipelines
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// BackoffPolicy configures the truncated-exponential retry used by
+// CommitCrossPipelineRequestWithBackoff.
+type BackoffPolicy struct {
+	// InitialDelay is the delay before the first retry.
+	InitialDelay time.Duration
+	// MaxDelay caps the delay of any single retry, before jitter.
+	MaxDelay time.Duration
+	// Multiplier grows the delay between successive retries.
+	Multiplier float64
+	// Jitter is the fraction, in [0, 1], the computed delay is randomly
+	// widened or narrowed by: delay * (1 +/- Jitter).
+	Jitter float64
+	// MaxElapsed bounds the total time spent retrying before giving up.
+	MaxElapsed time.Duration
+}
+
+// delay returns the truncated-exponential backoff for retry attempt n
+// (0-based): min(MaxDelay, InitialDelay * Multiplier^n) widened by a random
+// fraction of Jitter in either direction.
+func (p *BackoffPolicy) delay(n int) time.Duration {
+	d := float64(p.InitialDelay) * math.Pow(p.Multiplier, float64(n))
+	if max := float64(p.MaxDelay); d > max {
+		d = max
+	}
+
+	if p.Jitter > 0 {
+		d *= 1 + (rand.Float64()*2-1)*p.Jitter
+	}
+
+	if d < 0 {
+		d = 0
+	}
+
+	return time.Duration(d)
+}
+
+// CrossPipelineRequestErrorKind distinguishes why
+// CommitCrossPipelineRequestWithBackoff gave up retrying.
+type CrossPipelineRequestErrorKind uint8
+
+const (
+	// CrossPipelineRequestCancelled means the cancel channel fired, or the
+	// request's response channel was closed before a response arrived.
+	CrossPipelineRequestCancelled CrossPipelineRequestErrorKind = iota
+	// CrossPipelineRequestExhausted means BackoffPolicy.MaxElapsed was
+	// reached before the request was accepted.
+	CrossPipelineRequestExhausted
+)
+
+// CrossPipelineRequestError is returned by CommitCrossPipelineRequestWithBackoff
+// when it gives up, distinguishing a cancelled attempt from one that simply
+// ran out of retry budget.
+type CrossPipelineRequestError struct {
+	Kind     CrossPipelineRequestErrorKind
+	Attempts int
+	Elapsed  time.Duration
+	Cause    error
+}
+
+func (e *CrossPipelineRequestError) Error() string {
+	switch e.Kind {
+	case CrossPipelineRequestCancelled:
+		return fmt.Sprintf("cross pipeline request cancelled after %d attempt(s), %s elapsed",
+			e.Attempts, e.Elapsed)
+	default:
+		return fmt.Sprintf("cross pipeline request exhausted after %d attempt(s), %s elapsed: %v",
+			e.Attempts, e.Elapsed, e.Cause)
+	}
+}
+
+// TemporaryError is implemented by an UpstreamResponse.TaskError that wants
+// to be retried by CommitCrossPipelineRequestWithBackoff instead of treated
+// as a terminal application error, following the same convention as
+// net.Error.Temporary.
+type TemporaryError interface {
+	error
+	Temporary() bool
+}
+
+// isRetryable reports whether a TaskError from an UpstreamResponse should be
+// retried: either the response carried a RetryAfter hint, or the error
+// itself opts in via TemporaryError. Any other non-nil TaskError is a
+// terminal application error (bad request, validation failure, ...) and
+// must surface immediately instead of burning the retry budget.
+func isRetryable(response *UpstreamResponse) bool {
+	if response.RetryAfter > 0 {
+		return true
+	}
+
+	te, ok := response.TaskError.(TemporaryError)
+	return ok && te.Temporary()
+}
+
+// CommitCrossPipelineRequestWithBackoff commits request to ctx, retrying on
+// transient rejection according to policy: either ctx.CommitCrossPipelineRequest
+// returning a non-nil error (for example because the downstream pipeline's
+// queue is momentarily full), or an UpstreamResponse whose TaskError is
+// retryable per isRetryable. A terminal (non-retryable) TaskError is
+// returned immediately, alongside the response that carried it, without
+// consuming the retry budget. If the upstream pipeline responds with a
+// RetryAfter, the caller waits at least that long before the next attempt
+// and the exponential counter resets, treating the hint as cooperative rate
+// limiting rather than a failure. Cancellation is propagated via cancel, the
+// same channel passed through to ctx.CommitCrossPipelineRequest.
+func CommitCrossPipelineRequestWithBackoff(ctx PipelineContext, request *DownstreamRequest,
+	policy *BackoffPolicy, cancel <-chan struct{}) (*UpstreamResponse, error) {
+
+	start := time.Now()
+	attempt, n := 0, 0
+
+	cancelled := func() *CrossPipelineRequestError {
+		return &CrossPipelineRequestError{
+			Kind: CrossPipelineRequestCancelled, Attempts: attempt, Elapsed: time.Since(start),
+		}
+	}
+
+	for {
+		attempt++
+
+		var cause error
+		wait := policy.delay(n)
+		n++
+
+		err := ctx.CommitCrossPipelineRequest(request, cancel)
+		if err == nil {
+			select {
+			case response, ok := <-request.Response():
+				if !ok {
+					return nil, cancelled()
+				}
+
+				if response.TaskError == nil {
+					return response, nil
+				}
+
+				if !isRetryable(response) {
+					return response, response.TaskError
+				}
+
+				cause = response.TaskError
+				if response.RetryAfter > 0 {
+					n = 0
+					wait = response.RetryAfter
+				}
+			case <-cancel:
+				return nil, cancelled()
+			}
+		} else {
+			cause = err
+		}
+
+		if elapsed := time.Since(start); elapsed >= policy.MaxElapsed {
+			return nil, &CrossPipelineRequestError{
+				Kind: CrossPipelineRequestExhausted, Attempts: attempt, Elapsed: elapsed, Cause: cause,
+			}
+		}
+
+		if waitErr := waitCancellable(wait, cancel); waitErr != nil {
+			return nil, cancelled()
+		}
+	}
+}
+
+// waitCancellable sleeps for d, returning early with an error if cancel
+// fires first.
+func waitCancellable(d time.Duration, cancel <-chan struct{}) error {
+	if d <= 0 {
+		select {
+		case <-cancel:
+			return fmt.Errorf("wait cancelled")
+		default:
+			return nil
+		}
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-cancel:
+		return fmt.Errorf("wait cancelled")
+	}
+}
@@ -0,0 +1,205 @@
+package pipelines
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakePipelineContext struct {
+	PipelineContext
+	commit func(request *DownstreamRequest, cancel <-chan struct{}) error
+}
+
+func (f *fakePipelineContext) CommitCrossPipelineRequest(
+	request *DownstreamRequest, cancel <-chan struct{}) error {
+
+	return f.commit(request, cancel)
+}
+
+type temporaryError struct{ message string }
+
+func (e *temporaryError) Error() string   { return e.message }
+func (e *temporaryError) Temporary() bool { return true }
+
+func TestBackoffPolicyDelay(t *testing.T) {
+	policy := &BackoffPolicy{
+		InitialDelay: 10 * time.Millisecond,
+		MaxDelay:     100 * time.Millisecond,
+		Multiplier:   2,
+	}
+
+	cases := []struct {
+		n        int
+		expected time.Duration
+	}{
+		{0, 10 * time.Millisecond},
+		{1, 20 * time.Millisecond},
+		{2, 40 * time.Millisecond},
+		{3, 80 * time.Millisecond},
+		{4, 100 * time.Millisecond}, // would be 160ms, capped by MaxDelay
+		{10, 100 * time.Millisecond},
+	}
+
+	for _, c := range cases {
+		if got := policy.delay(c.n); got != c.expected {
+			t.Errorf("delay(%d) = %s, want %s", c.n, got, c.expected)
+		}
+	}
+}
+
+func TestBackoffPolicyDelayJitterBounds(t *testing.T) {
+	policy := &BackoffPolicy{
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     time.Second,
+		Multiplier:   1,
+		Jitter:       0.2,
+	}
+
+	low := 80 * time.Millisecond
+	high := 120 * time.Millisecond
+
+	for i := 0; i < 1000; i++ {
+		d := policy.delay(0)
+		if d < low || d > high {
+			t.Fatalf("delay(0) = %s, want within [%s, %s]", d, low, high)
+		}
+	}
+}
+
+func TestCommitCrossPipelineRequestWithBackoffSucceedsImmediately(t *testing.T) {
+	request := NewDownstreamRequest("up", "down", nil)
+	go request.Respond(&UpstreamResponse{}, nil)
+
+	ctx := &fakePipelineContext{commit: func(*DownstreamRequest, <-chan struct{}) error { return nil }}
+	policy := &BackoffPolicy{InitialDelay: time.Hour, MaxDelay: time.Hour, Multiplier: 2, MaxElapsed: time.Second}
+
+	response, err := CommitCrossPipelineRequestWithBackoff(ctx, request, policy, make(chan struct{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response == nil {
+		t.Fatal("expected a response")
+	}
+}
+
+func TestCommitCrossPipelineRequestWithBackoffRetryAfterResetsCounter(t *testing.T) {
+	request := NewDownstreamRequest("up", "down", nil)
+
+	go func() {
+		request.Respond(&UpstreamResponse{
+			TaskError:  errors.New("downstream rate limited"),
+			RetryAfter: 10 * time.Millisecond,
+		}, nil)
+		request.Respond(&UpstreamResponse{}, nil)
+	}()
+
+	ctx := &fakePipelineContext{commit: func(*DownstreamRequest, <-chan struct{}) error { return nil }}
+	// A large InitialDelay proves the RetryAfter hint (and its counter
+	// reset), not the policy's own backoff, governs the wait.
+	policy := &BackoffPolicy{InitialDelay: time.Hour, MaxDelay: time.Hour, Multiplier: 2, MaxElapsed: time.Second}
+
+	start := time.Now()
+	response, err := CommitCrossPipelineRequestWithBackoff(ctx, request, policy, make(chan struct{}))
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response == nil {
+		t.Fatal("expected a response")
+	}
+	if elapsed > 200*time.Millisecond {
+		t.Fatalf("elapsed %s, expected to honor RetryAfter (10ms) rather than InitialDelay (1h)", elapsed)
+	}
+}
+
+func TestCommitCrossPipelineRequestWithBackoffTerminalErrorReturnsImmediately(t *testing.T) {
+	request := NewDownstreamRequest("up", "down", nil)
+	terminal := errors.New("bad request")
+	go request.Respond(&UpstreamResponse{TaskError: terminal}, nil)
+
+	ctx := &fakePipelineContext{commit: func(*DownstreamRequest, <-chan struct{}) error { return nil }}
+	policy := &BackoffPolicy{InitialDelay: time.Hour, MaxDelay: time.Hour, Multiplier: 2, MaxElapsed: time.Second}
+
+	start := time.Now()
+	response, err := CommitCrossPipelineRequestWithBackoff(ctx, request, policy, make(chan struct{}))
+	elapsed := time.Since(start)
+
+	if err != terminal {
+		t.Fatalf("err = %v, want the terminal TaskError unwrapped", err)
+	}
+	if response == nil || response.TaskError != terminal {
+		t.Fatal("expected the response carrying the terminal error to be returned")
+	}
+	if elapsed > 200*time.Millisecond {
+		t.Fatalf("elapsed %s, terminal error must not be retried", elapsed)
+	}
+}
+
+func TestCommitCrossPipelineRequestWithBackoffTemporaryErrorRetries(t *testing.T) {
+	request := NewDownstreamRequest("up", "down", nil)
+
+	go func() {
+		request.Respond(&UpstreamResponse{TaskError: &temporaryError{"try again"}}, nil)
+		request.Respond(&UpstreamResponse{}, nil)
+	}()
+
+	ctx := &fakePipelineContext{commit: func(*DownstreamRequest, <-chan struct{}) error { return nil }}
+	policy := &BackoffPolicy{InitialDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, Multiplier: 2, MaxElapsed: time.Second}
+
+	response, err := CommitCrossPipelineRequestWithBackoff(ctx, request, policy, make(chan struct{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response == nil {
+		t.Fatal("expected a response")
+	}
+}
+
+func TestCommitCrossPipelineRequestWithBackoffExhausted(t *testing.T) {
+	ctx := &fakePipelineContext{commit: func(*DownstreamRequest, <-chan struct{}) error {
+		return errors.New("downstream queue full")
+	}}
+	request := NewDownstreamRequest("up", "down", nil)
+	policy := &BackoffPolicy{
+		InitialDelay: time.Millisecond,
+		MaxDelay:     2 * time.Millisecond,
+		Multiplier:   2,
+		MaxElapsed:   20 * time.Millisecond,
+	}
+
+	_, err := CommitCrossPipelineRequestWithBackoff(ctx, request, policy, make(chan struct{}))
+	cpErr, ok := err.(*CrossPipelineRequestError)
+	if !ok {
+		t.Fatalf("err = %v (%T), want *CrossPipelineRequestError", err, err)
+	}
+	if cpErr.Kind != CrossPipelineRequestExhausted {
+		t.Fatalf("Kind = %v, want CrossPipelineRequestExhausted", cpErr.Kind)
+	}
+}
+
+func TestCommitCrossPipelineRequestWithBackoffCancelled(t *testing.T) {
+	cancel := make(chan struct{})
+	close(cancel)
+
+	ctx := &fakePipelineContext{commit: func(*DownstreamRequest, <-chan struct{}) error {
+		return errors.New("downstream queue full")
+	}}
+	request := NewDownstreamRequest("up", "down", nil)
+	policy := &BackoffPolicy{
+		InitialDelay: time.Hour,
+		MaxDelay:     time.Hour,
+		Multiplier:   2,
+		MaxElapsed:   time.Hour,
+	}
+
+	_, err := CommitCrossPipelineRequestWithBackoff(ctx, request, policy, cancel)
+	cpErr, ok := err.(*CrossPipelineRequestError)
+	if !ok {
+		t.Fatalf("err = %v (%T), want *CrossPipelineRequestError", err, err)
+	}
+	if cpErr.Kind != CrossPipelineRequestCancelled {
+		t.Fatalf("Kind = %v, want CrossPipelineRequestCancelled", cpErr.Kind)
+	}
+}
@@ -3,6 +3,7 @@ package pipelines
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/hexdecteam/easegateway-types/task"
 )
@@ -29,12 +30,29 @@ type PipelineContext interface {
 	DataBucket(pluginName, pluginInstanceId string) PipelineContextDataBucket
 	// DeleteBucket deletes a data bucket.
 	DeleteBucket(pluginName, pluginInstanceId string) PipelineContextDataBucket
-	// Downstream pipeline calls PushCrossPipelineRequest to commit a request
+	// Downstream pipeline calls CommitCrossPipelineRequest to commit a request.
+	// Implementations must service the wait for a free slot and the cancel
+	// channel through Scheduler() rather than spawning a goroutine per call,
+	// so a pipeline under high RPS doesn't pin one goroutine per in-flight
+	// cross-pipeline request.
 	CommitCrossPipelineRequest(request *DownstreamRequest, cancel <-chan struct{}) error
-	// Upstream pipeline calls PopCrossPipelineRequest to claim a request
+	// Upstream pipeline calls ClaimCrossPipelineRequest to claim a request.
+	// Implementations must service the wait for an available request and
+	// the cancel channel through Scheduler() rather than spawning a
+	// goroutine per call, for the same reason as CommitCrossPipelineRequest.
 	ClaimCrossPipelineRequest(cancel <-chan struct{}) *DownstreamRequest
 	// Upstream pipeline calls CrossPipelineWIPRequestsCount to make sure how many requests are waiting process
 	CrossPipelineWIPRequestsCount(upstreamPipelineName string) int
+	// Ready reports whether the pipeline has finished preparing every
+	// plugin and is safe to route traffic to. A caller that adds mux
+	// entries for this pipeline (see controller.TrafficController) must
+	// poll Ready and wait for it to return true before doing so.
+	Ready() bool
+	// Scheduler returns the bounded worker pool tasks of the pipeline run on
+	Scheduler() Scheduler
+	// Tap returns the event stream the pipeline publishes lifecycle and
+	// plugin execution events to, independent of plugins.
+	Tap() Tap
 	// Close closes a PipelineContext
 	Close()
 }
@@ -136,6 +154,10 @@ type UpstreamResponse struct {
 	Data                 map[interface{}]interface{}
 	TaskError            error
 	TaskResultCode       task.TaskResultCode
+	// RetryAfter, when greater than zero, asks a caller retrying through
+	// CommitCrossPipelineRequestWithBackoff to wait at least this long
+	// before the next attempt, resetting its exponential counter.
+	RetryAfter time.Duration
 }
 
 ////
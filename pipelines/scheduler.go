@@ -0,0 +1,16 @@
+package pipelines
+
+// Scheduler runs work submitted by a PipelineContext on a bounded,
+// preallocated worker pool instead of spawning a goroutine per call, keeping
+// the number of live goroutines independent of request rate once the pool
+// is warm.
+type Scheduler interface {
+	// Submit schedules fn to run on the pool without blocking the caller.
+	// fn is expected to return promptly; long-running or blocking work
+	// should use SubmitWithCancel instead.
+	Submit(fn func())
+	// SubmitWithCancel schedules fn to run on the pool, handing it a cancel
+	// channel fn should select on to stop early, e.g. when the owning task
+	// is cancelled by the pipeline.
+	SubmitWithCancel(fn func(cancel <-chan struct{}))
+}
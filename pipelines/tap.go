@@ -0,0 +1,73 @@
+package pipelines
+
+import (
+	"github.com/hexdecteam/easegateway-types/task"
+)
+
+// TapEventKind identifies the kind of occurrence a TapEvent records.
+type TapEventKind uint8
+
+const (
+	TapPipelineStart TapEventKind = iota
+	TapPluginStart
+	TapPluginFinish
+	TapPipelineFinish
+	TapCrossPipelineRequest
+)
+
+// TapEvent is a single record of pipeline or plugin execution, emitted
+// regardless of what the running plugins do, and mirrors the TapEvent
+// message in tap.proto field for field. StartNano and EndNano are
+// monotonic nanosecond timestamps comparable only to each other within the
+// same process. Extensions carries forward-compatible, sink-specific
+// fields so new tap consumers don't require a schema change here.
+type TapEvent struct {
+	Kind         TapEventKind
+	PipelineName string
+	PluginName   string
+	TaskID       string
+	StartNano    int64
+	EndNano      int64
+	ResultCode   task.TaskResultCode
+	// Headers optionally carries serialized HTTPCtx request/response headers,
+	// keyed by header name.
+	Headers    map[string][]string
+	Extensions map[string]string
+}
+
+// TapSampler bounds how much of the tap stream is actually emitted.
+// Probability is the fraction of events kept, in [0, 1]. MaxPerSecond caps
+// the kept rate regardless of Probability; zero means unlimited.
+type TapSampler struct {
+	Probability  float64
+	MaxPerSecond int
+}
+
+// Tap is the event stream a PipelineContext publishes
+// PipelineStart/PluginStart/PluginFinish/PipelineFinish/CrossPipelineRequest
+// events to, over a transport (unix domain socket, TCP frame stream, or
+// in-process channel) chosen by the Tap implementation. A transport that
+// writes to a byte stream should frame each marshalled TapEvent with
+// WriteTapFrame/ReadTapFrame (tap_frame.go) per the wire schema in
+// tap.proto; this package defines only that framing and the Go/proto
+// shapes, not a transport or a protobuf codec.
+type Tap interface {
+	// Emit publishes event. Implementations must not block the caller on a
+	// slow subscriber; a full transport should drop events rather than
+	// back-pressure the pipeline.
+	Emit(event *TapEvent)
+	// SetSampler replaces the active sampling policy. A nil sampler means
+	// emit every event.
+	SetSampler(sampler *TapSampler)
+	// Close releases the transport.
+	Close() error
+}
+
+// TapReader lets an external process subscribe to a Tap's event stream,
+// for example by pairing ReadTapFrame with a tap.proto TapEvent
+// unmarshaller over a byte-stream transport.
+type TapReader interface {
+	// Read blocks until the next TapEvent is available or the stream ends.
+	Read() (*TapEvent, error)
+	Close() error
+}
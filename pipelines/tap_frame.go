@@ -0,0 +1,56 @@
+package pipelines
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// maxTapFrameSize bounds a single frame's payload, guarding a reader against
+// a corrupt or malicious length prefix demanding an unreasonable allocation.
+const maxTapFrameSize = 16 << 20 // 16 MiB
+
+// WriteTapFrame writes payload (a TapEvent marshalled per tap.proto) to w as
+// one frame: a 4-byte big-endian length prefix followed by payload itself,
+// the same length-prefixed framing DNS tap and frame-streams use. It is the
+// only part of the wire format this package implements; the protobuf
+// marshalling of payload itself is left to the binary wiring up a Tap.
+func WriteTapFrame(w io.Writer, payload []byte) error {
+	if len(payload) > maxTapFrameSize {
+		return fmt.Errorf("pipelines: tap frame payload of %d bytes exceeds max %d",
+			len(payload), maxTapFrameSize)
+	}
+
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(payload)))
+
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+
+	_, err := w.Write(payload)
+
+	return err
+}
+
+// ReadTapFrame reads one length-prefixed frame written by WriteTapFrame and
+// returns its payload, ready for a tap.proto TapEvent unmarshaller.
+func ReadTapFrame(r io.Reader) ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[:])
+	if size > maxTapFrameSize {
+		return nil, fmt.Errorf("pipelines: tap frame declares %d bytes, exceeds max %d",
+			size, maxTapFrameSize)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
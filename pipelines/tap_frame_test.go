@@ -0,0 +1,75 @@
+package pipelines
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestTapFrameRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	payload := []byte("a TapEvent marshalled elsewhere")
+
+	if err := WriteTapFrame(&buf, payload); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := ReadTapFrame(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatalf("got %q, want %q", got, payload)
+	}
+}
+
+func TestTapFrameMultipleFramesInOrder(t *testing.T) {
+	var buf bytes.Buffer
+	payloads := [][]byte{[]byte("one"), []byte("two"), []byte("three")}
+
+	for _, p := range payloads {
+		if err := WriteTapFrame(&buf, p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for _, want := range payloads {
+		got, err := ReadTapFrame(&buf)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestWriteTapFrameRejectsOversizedPayload(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := WriteTapFrame(&buf, make([]byte, maxTapFrameSize+1)); err == nil {
+		t.Fatal("expected an error for a payload over maxTapFrameSize")
+	}
+}
+
+func TestReadTapFrameRejectsOversizedLengthPrefix(t *testing.T) {
+	var buf bytes.Buffer
+	// a length prefix declaring more than maxTapFrameSize, with no body
+	if err := WriteTapFrame(&buf, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// overwrite the length prefix we just wrote with an oversized value
+	oversized := make([]byte, 4)
+	oversized[0] = 0xff
+	copy(buf.Bytes()[:4], oversized)
+
+	if _, err := ReadTapFrame(&buf); err == nil {
+		t.Fatal("expected an error for an oversized length prefix")
+	}
+}
+
+func TestReadTapFrameShortReadError(t *testing.T) {
+	if _, err := ReadTapFrame(bytes.NewReader([]byte{0, 0, 0})); err != io.ErrUnexpectedEOF {
+		t.Fatalf("err = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
@@ -0,0 +1,104 @@
+package plugins
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hexdecteam/easegateway-types/task"
+)
+
+// LogRecord is one pipeline task execution, harvested for a configured
+// LogDriver. Fields carries arbitrary key/value data gathered from the
+// pipeline's PipelineContextDataBucket in addition to the fixed ones below.
+type LogRecord struct {
+	PipelineName    string
+	PluginName      string
+	TaskResultCode  task.TaskResultCode
+	Latency         time.Duration
+	RequestHeaders  map[string][]string
+	ResponseHeaders map[string][]string
+	Fields          map[string]interface{}
+}
+
+// LogDriver delivers LogRecords to a sink, following the multi-driver
+// pattern common in container runtimes (json-file, fluentd, gelf, journald,
+// gcplogs, splunk, syslog, ...). A driver must not block the caller for
+// long; the pipeline scheduler fans out records through a bounded queue and
+// drops them on overflow rather than let a slow driver back-pressure
+// HTTPMux.ServeHTTP.
+type LogDriver interface {
+	Write(record *LogRecord) error
+	Close() error
+}
+
+// LogDriverConstructor builds a LogDriver from its driver-specific config.
+type LogDriverConstructor func(config map[string]string) (LogDriver, error)
+
+var (
+	logDriversLock sync.RWMutex
+	logDrivers     = make(map[string]LogDriverConstructor)
+)
+
+// RegisterLogDriver makes a LogDriver available under name for pipeline
+// config to select. It is meant to be called from the init() of a driver
+// implementation package.
+func RegisterLogDriver(name string, constructor LogDriverConstructor) error {
+	logDriversLock.Lock()
+	defer logDriversLock.Unlock()
+
+	if _, exists := logDrivers[name]; exists {
+		return fmt.Errorf("log driver %s already registered", name)
+	}
+
+	logDrivers[name] = constructor
+
+	return nil
+}
+
+// NewLogDriver constructs the LogDriver registered under name.
+func NewLogDriver(name string, config map[string]string) (LogDriver, error) {
+	logDriversLock.RLock()
+	constructor, exists := logDrivers[name]
+	logDriversLock.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("log driver %s not registered", name)
+	}
+
+	return constructor(config)
+}
+
+// LogDriverSelector is the piece of pipeline config that picks a log driver
+// and sizes its fan-out queue, letting driver selection live in the same
+// declarative config as the rest of a pipeline.
+type LogDriverSelector interface {
+	// LogDriverName names the registered driver to use, e.g. "json-stdout"
+	// or "fluentd".
+	LogDriverName() string
+	// LogDriverConfig is passed verbatim to the driver's LogDriverConstructor.
+	LogDriverConfig() map[string]string
+	// LogDriverQueueSize bounds the fan-out queue in front of the driver;
+	// zero or negative selects defaultLogDriverQueueSize.
+	LogDriverQueueSize() int
+}
+
+const defaultLogDriverQueueSize = 1024
+
+// NewConfiguredLogDriver builds the driver selector.LogDriverName() names
+// and wraps it in a bounded, drop-on-overflow queue (see NewQueuedLogDriver)
+// sized by selector.LogDriverQueueSize(), so a pipeline only has to hold a
+// LogDriverSelector in its config to get a ready-to-use LogDriver.
+func NewConfiguredLogDriver(selector LogDriverSelector) (LogDriver, error) {
+	driver, err := NewLogDriver(selector.LogDriverName(), selector.LogDriverConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	size := selector.LogDriverQueueSize()
+	if size <= 0 {
+		size = defaultLogDriverQueueSize
+	}
+
+	return NewQueuedLogDriver(driver, size), nil
+}
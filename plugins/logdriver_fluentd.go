@@ -0,0 +1,241 @@
+package plugins
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+)
+
+func init() {
+	RegisterLogDriver("fluentd", func(config map[string]string) (LogDriver, error) {
+		network := config["network"]
+		if network == "" {
+			network = "tcp"
+		}
+
+		address := config["address"]
+		if address == "" {
+			return nil, fmt.Errorf("fluentd log driver requires an address")
+		}
+
+		tag := config["tag"]
+		if tag == "" {
+			tag = "easegateway"
+		}
+
+		return NewFluentdLogDriver(network, address, tag)
+	})
+}
+
+// FluentdLogDriver is the reference "fluentd" LogDriver: it speaks the
+// Fluentd Forward Protocol's Message Mode, sending one
+// [tag, time, record] msgpack-encoded entry per LogRecord over a
+// long-lived connection.
+type FluentdLogDriver struct {
+	tag string
+
+	lock sync.Mutex
+	conn net.Conn
+}
+
+// NewFluentdLogDriver dials address over network (e.g. "tcp", "unix") and
+// returns a FluentdLogDriver tagging every record with tag.
+func NewFluentdLogDriver(network, address, tag string) (*FluentdLogDriver, error) {
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FluentdLogDriver{tag: tag, conn: conn}, nil
+}
+
+func (d *FluentdLogDriver) Write(record *LogRecord) error {
+	entry := fluentdEntry(record)
+
+	payload, err := msgpackEncode([]interface{}{d.tag, time.Now().Unix(), entry})
+	if err != nil {
+		return err
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	_, err = d.conn.Write(payload)
+
+	return err
+}
+
+func (d *FluentdLogDriver) Close() error {
+	return d.conn.Close()
+}
+
+// fluentdEntry flattens a LogRecord into the map the Forward Protocol
+// expects as its record field, merging in the record's own Fields.
+func fluentdEntry(record *LogRecord) map[string]interface{} {
+	entry := map[string]interface{}{
+		"pipeline_name":    record.PipelineName,
+		"plugin_name":      record.PluginName,
+		"task_result_code": int64(record.TaskResultCode),
+		"latency_ms":       record.Latency.Seconds() * 1000,
+	}
+
+	if record.RequestHeaders != nil {
+		entry["request_headers"] = headersToInterface(record.RequestHeaders)
+	}
+
+	if record.ResponseHeaders != nil {
+		entry["response_headers"] = headersToInterface(record.ResponseHeaders)
+	}
+
+	for k, v := range record.Fields {
+		entry[k] = v
+	}
+
+	return entry
+}
+
+func headersToInterface(headers map[string][]string) map[string]interface{} {
+	out := make(map[string]interface{}, len(headers))
+	for k, values := range headers {
+		vs := make([]interface{}, len(values))
+		for i, v := range values {
+			vs[i] = v
+		}
+		out[k] = vs
+	}
+
+	return out
+}
+
+// msgpackEncode is a minimal MessagePack encoder covering the subset of
+// types a LogRecord can produce: nil, bool, int, int64, uint64, float64,
+// string, []byte, []interface{} and map[string]interface{}; any other type,
+// including the other numeric kinds, is rejected with an error.
+// It exists so FluentdLogDriver has no third-party dependency; it is not a
+// general-purpose codec.
+func msgpackEncode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := msgpackEncodeTo(&buf, v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func msgpackEncodeTo(buf *bytes.Buffer, v interface{}) error {
+	switch t := v.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if t {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case string:
+		return msgpackEncodeString(buf, t)
+	case []byte:
+		msgpackEncodeUint32(buf, 0xc6, uint32(len(t)))
+		buf.Write(t)
+	case int:
+		return msgpackEncodeInt(buf, int64(t))
+	case int64:
+		return msgpackEncodeInt(buf, t)
+	case uint64:
+		if t <= math.MaxUint32 {
+			msgpackEncodeUint32(buf, 0xce, uint32(t))
+		} else {
+			buf.WriteByte(0xcf)
+			for i := 56; i >= 0; i -= 8 {
+				buf.WriteByte(byte(t >> uint(i)))
+			}
+		}
+	case float64:
+		return msgpackEncodeFloat(buf, t)
+	case []interface{}:
+		return msgpackEncodeArray(buf, t)
+	case map[string]interface{}:
+		return msgpackEncodeMap(buf, t)
+	default:
+		return fmt.Errorf("msgpack: unsupported type %T", v)
+	}
+
+	return nil
+}
+
+func msgpackEncodeString(buf *bytes.Buffer, s string) error {
+	msgpackEncodeUint32(buf, 0xdb, uint32(len(s)))
+	buf.WriteString(s)
+
+	return nil
+}
+
+func msgpackEncodeInt(buf *bytes.Buffer, n int64) error {
+	if n >= 0 {
+		return msgpackEncodeTo(buf, uint64(n))
+	}
+
+	buf.WriteByte(0xd3)
+	var b [8]byte
+	for i := 7; i >= 0; i-- {
+		b[i] = byte(n)
+		n >>= 8
+	}
+	buf.Write(b[:])
+
+	return nil
+}
+
+func msgpackEncodeFloat(buf *bytes.Buffer, f float64) error {
+	buf.WriteByte(0xcb)
+	bits := math.Float64bits(f)
+	var b [8]byte
+	for i := 7; i >= 0; i-- {
+		b[i] = byte(bits)
+		bits >>= 8
+	}
+	buf.Write(b[:])
+
+	return nil
+}
+
+// msgpackEncodeUint32 writes tag followed by n as a big-endian uint32; it is
+// reused for the string/bin/array/map length prefixes and for unsigned
+// values that fit in 32 bits, which all share this shape.
+func msgpackEncodeUint32(buf *bytes.Buffer, tag byte, n uint32) {
+	buf.WriteByte(tag)
+	buf.WriteByte(byte(n >> 24))
+	buf.WriteByte(byte(n >> 16))
+	buf.WriteByte(byte(n >> 8))
+	buf.WriteByte(byte(n))
+}
+
+func msgpackEncodeArray(buf *bytes.Buffer, a []interface{}) error {
+	msgpackEncodeUint32(buf, 0xdd, uint32(len(a)))
+
+	for _, e := range a {
+		if err := msgpackEncodeTo(buf, e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func msgpackEncodeMap(buf *bytes.Buffer, m map[string]interface{}) error {
+	msgpackEncodeUint32(buf, 0xdf, uint32(len(m)))
+
+	for k, e := range m {
+		if err := msgpackEncodeString(buf, k); err != nil {
+			return err
+		}
+		if err := msgpackEncodeTo(buf, e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package plugins
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// QueuedLogDriver wraps a LogDriver with a bounded queue drained by a single
+// background goroutine, so a pipeline can hand it one record per task
+// (typically from pipelines.Scheduler.Submit, alongside the task itself)
+// without ever blocking on a slow sink. When the queue is full, Write drops
+// the record instead of blocking; Dropped reports how many were lost.
+type QueuedLogDriver struct {
+	driver  LogDriver
+	records chan *LogRecord
+	dropped uint64
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewQueuedLogDriver starts draining into driver through a queue of
+// queueSize records. Close stops the drain goroutine and closes driver.
+func NewQueuedLogDriver(driver LogDriver, queueSize int) *QueuedLogDriver {
+	if queueSize <= 0 {
+		queueSize = defaultLogDriverQueueSize
+	}
+
+	q := &QueuedLogDriver{
+		driver:  driver,
+		records: make(chan *LogRecord, queueSize),
+		done:    make(chan struct{}),
+	}
+
+	go q.drain()
+
+	return q
+}
+
+func (q *QueuedLogDriver) drain() {
+	defer close(q.done)
+
+	for record := range q.records {
+		q.driver.Write(record)
+	}
+}
+
+// Write enqueues record, dropping it without error if the queue is full.
+func (q *QueuedLogDriver) Write(record *LogRecord) error {
+	select {
+	case q.records <- record:
+		return nil
+	default:
+		atomic.AddUint64(&q.dropped, 1)
+		return nil
+	}
+}
+
+// Dropped returns the number of records dropped so far because the queue
+// was full.
+func (q *QueuedLogDriver) Dropped() uint64 {
+	return atomic.LoadUint64(&q.dropped)
+}
+
+// Close stops accepting new records, waits for the queue to drain, and
+// closes the wrapped driver.
+func (q *QueuedLogDriver) Close() error {
+	q.closeOnce.Do(func() {
+		close(q.records)
+	})
+	<-q.done
+
+	return q.driver.Close()
+}
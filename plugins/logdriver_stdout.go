@@ -0,0 +1,60 @@
+package plugins
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+func init() {
+	RegisterLogDriver("json-stdout", func(config map[string]string) (LogDriver, error) {
+		return NewJSONStdoutLogDriver(os.Stdout), nil
+	})
+}
+
+// jsonLogRecord is the wire shape LogRecord is written as: LogRecord with
+// Latency and TaskResultCode converted to JSON-friendly types.
+type jsonLogRecord struct {
+	PipelineName    string                 `json:"pipeline_name"`
+	PluginName      string                 `json:"plugin_name"`
+	TaskResultCode  uint32                 `json:"task_result_code"`
+	LatencyMs       float64                `json:"latency_ms"`
+	RequestHeaders  map[string][]string    `json:"request_headers,omitempty"`
+	ResponseHeaders map[string][]string    `json:"response_headers,omitempty"`
+	Fields          map[string]interface{} `json:"fields,omitempty"`
+}
+
+// JSONStdoutLogDriver is the reference "json-stdout" LogDriver: one
+// JSON-encoded line per LogRecord, written to an io.Writer (os.Stdout when
+// built through the registered "json-stdout" driver).
+type JSONStdoutLogDriver struct {
+	lock sync.Mutex
+	w    io.Writer
+	enc  *json.Encoder
+}
+
+// NewJSONStdoutLogDriver returns a JSONStdoutLogDriver writing to w.
+func NewJSONStdoutLogDriver(w io.Writer) *JSONStdoutLogDriver {
+	return &JSONStdoutLogDriver{w: w, enc: json.NewEncoder(w)}
+}
+
+func (d *JSONStdoutLogDriver) Write(record *LogRecord) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.enc.Encode(&jsonLogRecord{
+		PipelineName:    record.PipelineName,
+		PluginName:      record.PluginName,
+		TaskResultCode:  uint32(record.TaskResultCode),
+		LatencyMs:       record.Latency.Seconds() * 1000,
+		RequestHeaders:  record.RequestHeaders,
+		ResponseHeaders: record.ResponseHeaders,
+		Fields:          record.Fields,
+	})
+}
+
+// Close is a no-op: JSONStdoutLogDriver doesn't own the lifecycle of w.
+func (d *JSONStdoutLogDriver) Close() error {
+	return nil
+}
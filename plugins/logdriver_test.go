@@ -0,0 +1,151 @@
+package plugins
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func registerTestLogDriver(t *testing.T, name string, constructor LogDriverConstructor) {
+	t.Helper()
+
+	if err := RegisterLogDriver(name, constructor); err != nil {
+		t.Fatalf("RegisterLogDriver(%s) failed: %v", name, err)
+	}
+
+	t.Cleanup(func() {
+		logDriversLock.Lock()
+		delete(logDrivers, name)
+		logDriversLock.Unlock()
+	})
+}
+
+func TestRegisterLogDriverDuplicate(t *testing.T) {
+	name := "test-duplicate"
+	noop := func(map[string]string) (LogDriver, error) { return nil, nil }
+
+	registerTestLogDriver(t, name, noop)
+
+	if err := RegisterLogDriver(name, noop); err == nil {
+		t.Fatal("expected an error registering an already-registered driver name")
+	}
+}
+
+func TestNewLogDriverUnknownName(t *testing.T) {
+	if _, err := NewLogDriver("does-not-exist", nil); err == nil {
+		t.Fatal("expected an error constructing an unregistered driver name")
+	}
+}
+
+func TestNewLogDriverConstructs(t *testing.T) {
+	name := "test-construct"
+	registerTestLogDriver(t, name, func(config map[string]string) (LogDriver, error) {
+		if config["k"] != "v" {
+			return nil, errors.New("config not passed through")
+		}
+		return &JSONStdoutLogDriver{}, nil
+	})
+
+	driver, err := NewLogDriver(name, map[string]string{"k": "v"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if driver == nil {
+		t.Fatal("expected a driver")
+	}
+}
+
+func TestJSONStdoutLogDriverWritesOneLinePerRecord(t *testing.T) {
+	var buf bytes.Buffer
+	driver := NewJSONStdoutLogDriver(&buf)
+
+	record := &LogRecord{
+		PipelineName: "pipe",
+		PluginName:   "plug",
+		Latency:      5 * time.Millisecond,
+		Fields:       map[string]interface{}{"k": "v"},
+	}
+
+	if err := driver.Write(record); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := driver.Write(record); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %q", len(lines), buf.String())
+	}
+
+	var decoded jsonLogRecord
+	if err := json.Unmarshal([]byte(lines[0]), &decoded); err != nil {
+		t.Fatalf("failed to decode line: %v", err)
+	}
+	if decoded.PipelineName != "pipe" || decoded.PluginName != "plug" {
+		t.Fatalf("decoded = %+v, want pipeline/plugin preserved", decoded)
+	}
+	if decoded.LatencyMs != 5 {
+		t.Fatalf("LatencyMs = %v, want 5", decoded.LatencyMs)
+	}
+}
+
+func TestQueuedLogDriverDropsOnOverflow(t *testing.T) {
+	blocking := make(chan struct{})
+	started := make(chan struct{}, 1)
+
+	driver := &blockingLogDriver{blocking: blocking, started: started}
+	queued := NewQueuedLogDriver(driver, 1)
+
+	// first write is picked up by drain() and blocks it; wait for that to
+	// happen so the queue (capacity 1) + in-flight write is fully occupied.
+	if err := queued.Write(&LogRecord{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-started
+
+	if err := queued.Write(&LogRecord{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := queued.Write(&LogRecord{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dropped := queued.Dropped(); dropped == 0 {
+		t.Fatal("expected at least one dropped record once the queue was full")
+	}
+
+	close(blocking)
+
+	if err := queued.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !driver.closed {
+		t.Fatal("expected the wrapped driver to be closed")
+	}
+}
+
+type blockingLogDriver struct {
+	blocking <-chan struct{}
+	started  chan struct{}
+	closed   bool
+}
+
+func (d *blockingLogDriver) Write(record *LogRecord) error {
+	select {
+	case d.started <- struct{}{}:
+	default:
+	}
+	<-d.blocking
+
+	return nil
+}
+
+func (d *blockingLogDriver) Close() error {
+	d.closed = true
+	return nil
+}
@@ -37,6 +37,21 @@ type Plugin interface {
 	Close()
 }
 
+// Continuation resumes a task suspended by AsyncPlugin.Start because it
+// would otherwise have blocked on I/O. The scheduler invokes it on the
+// pipeline's pipelines.Scheduler once the pending operation is ready to
+// make progress; it follows the same error rules as Plugin.Run.
+type Continuation func() error
+
+// AsyncPlugin is an optional, nonblocking alternative to Plugin. A plugin
+// implementing it is started rather than run: Start must not block on I/O
+// and instead returns a Continuation to be resumed later, letting the
+// pipeline keep the task off any blocking call while still making progress
+// through pipelines.Scheduler.
+type AsyncPlugin interface {
+	Start(ctx pipelines.PipelineContext, t task.Task) (Continuation, error)
+}
+
 type Constructor func(conf Config) (Plugin, PluginType, bool, error)
 
 type Config interface {
@@ -48,6 +63,27 @@ type ConfigConstructor func() Config
 
 ////
 
+// ExternalConfig is the Config of a plugin whose Plugin implementation runs
+// out of process, reached over the gRPC surface defined by plugins/rpc.
+type ExternalConfig interface {
+	Config
+	// Command returns the path and arguments used to launch the plugin's
+	// child process.
+	Command() (path string, args []string)
+	// HandshakeTimeout bounds how long the initial gRPC handshake and
+	// version negotiation with the child process may take.
+	HandshakeTimeout() time.Duration
+}
+
+// ExternalConstructor builds a Plugin backed by a child process launched
+// according to conf, instead of one running in the gateway process. The
+// returned Plugin proxies Prepare/Run/CleanUp/Close over an rpc.Client;
+// a crashed child is restarted by an rpc.Supervisor, which surfaces the same
+// "needs reconstruction" error semantics documented on Plugin.
+type ExternalConstructor func(conf ExternalConfig) (Plugin, PluginType, bool, error)
+
+////
+
 type SizedReadCloser interface {
 	io.ReadCloser
 	// Size indicates the available bytes length of reader
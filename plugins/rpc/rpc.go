@@ -0,0 +1,129 @@
+// Package rpc defines the gRPC surface used to run a plugins.Plugin out of
+// process: handshake and version negotiation, bidirectional streams for
+// Prepare/Run/CleanUp/Close, and proxies for the host-side types a plugin
+// needs to act on (HTTPCtx, Header, PipelineContextDataBucket) without
+// linking against the host process.
+package rpc
+
+import (
+	"io"
+	"time"
+
+	"github.com/hexdecteam/easegateway-types/plugins"
+	"github.com/hexdecteam/easegateway-types/task"
+)
+
+// ProtocolVersion is negotiated during Handshake; a host and plugin whose
+// ProtocolVersion differ refuse to talk rather than risk a silent mismatch.
+type ProtocolVersion uint32
+
+// Handshake is exchanged once, before any Prepare/Run/CleanUp/Close call, to
+// negotiate ProtocolVersion and let the host authenticate the child process.
+type Handshake struct {
+	ProtocolVersion ProtocolVersion
+	PluginName      string
+	MagicCookie     string
+}
+
+// Client is the host-side handle to an out-of-process plugin. It implements
+// plugins.Plugin by forwarding every call over a stream whose initial
+// handshake Supervisor.Start already performed by the time it returns a
+// Client; callers never need to handshake one themselves.
+type Client interface {
+	plugins.Plugin
+	// Handshake re-negotiates ProtocolVersion over the existing stream and
+	// returns the result, without affecting the version Supervisor.Start
+	// already negotiated. It exists for long-lived connections that want to
+	// reconfirm protocol compatibility, for example after a network blip;
+	// it is never required before calling the embedded plugins.Plugin
+	// methods.
+	Handshake() (ProtocolVersion, error)
+}
+
+// TaskPayload is the wire representation of a task.Task crossing the
+// process boundary: the fields a plugin needs to inspect or mutate, without
+// requiring the child process to link the host's task package.
+type TaskPayload struct {
+	ID         string
+	ResultCode task.TaskResultCode
+	Error      error
+	Data       map[interface{}]interface{}
+}
+
+// BodyChunk is one frame of an HTTPCtx body streamed through
+// plugins.SizedReadCloser; Size mirrors SizedReadCloser.Size() on the first
+// chunk and is zero thereafter.
+type BodyChunk struct {
+	Data []byte
+	Size int64
+	EOF  bool
+}
+
+// HeaderOp is a single CRUD operation against a plugins.Header, proxied from
+// the child process back to the host-owned HTTPCtx.
+type HeaderOp struct {
+	Op    HeaderOpKind
+	Key   string
+	Value string
+}
+
+type HeaderOpKind uint8
+
+const (
+	HeaderGet HeaderOpKind = iota
+	HeaderSet
+	HeaderAdd
+)
+
+// HTTPCtxProxy lets an out-of-process plugin act on the host's plugins.HTTPCtx
+// without holding a direct reference to it: body reads are chunked through
+// BodyChunk, single-key header mutations are replayed as HeaderOp calls, and
+// the VisitAll* methods return the full header set in one round trip since
+// plugins.Header.VisitAll's per-pair callback can't cross the RPC boundary.
+type HTTPCtxProxy interface {
+	RequestHeader(op HeaderOp) (string, error)
+	ResponseHeader(op HeaderOp) (string, error)
+	VisitAllRequestHeader() (map[string][]string, error)
+	VisitAllResponseHeader() (map[string][]string, error)
+	BodyReader() (io.ReadCloser, error)
+	Write(p []byte) (int, error)
+}
+
+// DataBucketProxy mirrors pipelines.PipelineContextDataBucket for a plugin
+// instance running out of process, with one adaptation:
+// QueryDataWithBindDefault takes a materialized defaultValue rather than a
+// pipelines.DefaultValueFunc, since a function value cannot cross the RPC
+// boundary. Callers that need the "compute the default lazily" behavior of
+// the in-process API must compute it before issuing the call.
+type DataBucketProxy interface {
+	BindData(key, value interface{}) (interface{}, error)
+	QueryData(key interface{}) interface{}
+	QueryDataWithBindDefault(key, defaultValue interface{}) (interface{}, error)
+	UnbindData(key interface{}) interface{}
+}
+
+// Supervisor owns the lifecycle of a plugin child process: launching it,
+// performing the initial Handshake on its behalf, and restarting it on
+// crash. A restart surfaces to the host as the same "needs reconstruction"
+// error Plugin.Run documents, so callers don't need to special-case
+// out-of-process plugins.
+type Supervisor interface {
+	// Start launches the child process, performs its initial handshake, and
+	// returns a Client ready to use; the caller never calls Client.Handshake
+	// itself unless it wants to re-negotiate later.
+	Start() (Client, error)
+	// Restart kills the current child process, if any, and starts a new one.
+	Restart() (Client, error)
+	// Stop terminates the child process and releases resources.
+	Stop() error
+}
+
+// SupervisorConfig configures a Supervisor.
+type SupervisorConfig struct {
+	Command          string
+	Args             []string
+	HandshakeTimeout time.Duration
+	// MaxRestarts bounds how many times Supervisor restarts a crashing
+	// child before giving up; zero means unlimited.
+	MaxRestarts int
+}